@@ -0,0 +1,114 @@
+// Package backend lets the proxy also *be* the lightning address provider
+// for a set of locally-owned addresses, instead of only forwarding to
+// remote providers. A Backend answers the same three lookups the proxy
+// otherwise fetches over HTTP - lnurlp metadata, the lnurlp callback, and
+// keysend - against a node the operator actually controls.
+package backend
+
+import (
+	"context"
+	"fmt"
+)
+
+// Kind selects which Backend implementation Config.Backend wires up.
+type Kind string
+
+const (
+	KindNone   Kind = "none"
+	KindLND    Kind = "lnd"
+	KindCLN    Kind = "cln"
+	KindCharge Kind = "charge"
+)
+
+// LNURLPMetadata is the lnurlp well-known response for a local address.
+type LNURLPMetadata struct {
+	Callback    string `json:"callback"`
+	MaxSendable int64  `json:"maxSendable"`
+	MinSendable int64  `json:"minSendable"`
+	Metadata    string `json:"metadata"`
+	Tag         string `json:"tag"`
+	AllowsNostr bool   `json:"allowsNostr,omitempty"`
+	NostrPubkey string `json:"nostrPubkey,omitempty"`
+}
+
+// KeysendMetadata is the keysend well-known response for a local address.
+type KeysendMetadata struct {
+	Pubkey string `json:"pubkey"`
+}
+
+// Backend is implemented by each node type the proxy can front.
+type Backend interface {
+	// LNURLPMetadata returns the lnurlp well-known payload for user.
+	LNURLPMetadata(ctx context.Context, user string) (*LNURLPMetadata, error)
+	// LNURLPCallback mints a bolt11 invoice for user for amountMsat
+	// millisatoshis, described by comment. It does not accept a NIP-57 zap
+	// request: none of the backends below hold the Nostr key material a
+	// zap receipt would need to be signed with, so Backend.LNURLPMetadata
+	// never advertises allowsNostr and callers reject a "nostr" param
+	// before reaching here.
+	LNURLPCallback(ctx context.Context, user string, amountMsat int64, comment string) (bolt11 string, err error)
+	// Keysend returns the keysend well-known payload for user.
+	Keysend(ctx context.Context, user string) (*KeysendMetadata, error)
+}
+
+// Config configures New. Only the fields relevant to the selected Kind
+// need to be set.
+type Config struct {
+	Backend Kind
+
+	LocalDomain string
+
+	// MinSendableMsat/MaxSendableMsat bound the amount a payer may request
+	// in the lnurlp metadata response, per LUD-06.
+	MinSendableMsat int64
+	MaxSendableMsat int64
+
+	// LND
+	LNDAddress     string
+	LNDMacaroonHex string
+	LNDTLSCertPath string
+
+	// CLN (REST plugin)
+	CLNRestURL string
+	CLNRune    string
+
+	// lightning-charge-style HTTP
+	ChargeAPI   string
+	ChargeToken string
+}
+
+// ErrNotConfigured is returned by backends that are selected but missing
+// the configuration they need to operate.
+var ErrNotConfigured = fmt.Errorf("backend not configured")
+
+// New builds the Backend selected by cfg.Backend. KindNone (the default)
+// returns a noopBackend so callers can fall through to proxy behavior
+// without a nil check everywhere.
+func New(cfg Config) (Backend, error) {
+	switch cfg.Backend {
+	case "", KindNone:
+		return noopBackend{}, nil
+	case KindLND:
+		return newLNDBackend(cfg)
+	case KindCLN:
+		return newCLNBackend(cfg)
+	case KindCharge:
+		return newChargeBackend(cfg)
+	default:
+		return nil, fmt.Errorf("unknown backend %q", cfg.Backend)
+	}
+}
+
+type noopBackend struct{}
+
+func (noopBackend) LNURLPMetadata(ctx context.Context, user string) (*LNURLPMetadata, error) {
+	return nil, ErrNotConfigured
+}
+
+func (noopBackend) LNURLPCallback(ctx context.Context, user string, amountMsat int64, comment string) (string, error) {
+	return "", ErrNotConfigured
+}
+
+func (noopBackend) Keysend(ctx context.Context, user string) (*KeysendMetadata, error) {
+	return nil, ErrNotConfigured
+}
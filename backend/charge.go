@@ -0,0 +1,86 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// chargeBackend talks to a lightning-charge-style HTTP API, authenticating
+// with a bearer token via HTTP basic auth (the convention lightning-charge
+// itself uses: empty username, token as password).
+type chargeBackend struct {
+	cfg    Config
+	client *http.Client
+}
+
+func newChargeBackend(cfg Config) (Backend, error) {
+	if cfg.ChargeAPI == "" || cfg.ChargeToken == "" {
+		return nil, fmt.Errorf("charge backend: %w: LN_CHARGE_API and LN_CHARGE_TOKEN are required", ErrNotConfigured)
+	}
+	return &chargeBackend{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (b *chargeBackend) post(ctx context.Context, path string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.ChargeAPI+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("charge backend: failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("", b.cfg.ChargeToken)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("charge backend: request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("charge backend: %s returned %d", path, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (b *chargeBackend) LNURLPMetadata(ctx context.Context, user string) (*LNURLPMetadata, error) {
+	meta, err := lnurlpMetadataString(b.cfg.LocalDomain, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LNURLPMetadata{
+		Callback:    fmt.Sprintf("https://%s/.well-known/lnurlp/%s/callback", b.cfg.LocalDomain, user),
+		MinSendable: b.cfg.MinSendableMsat,
+		MaxSendable: b.cfg.MaxSendableMsat,
+		Metadata:    meta,
+		Tag:         "payRequest",
+	}, nil
+}
+
+func (b *chargeBackend) LNURLPCallback(ctx context.Context, user string, amountMsat int64, comment string) (string, error) {
+	meta, err := lnurlpMetadataString(b.cfg.LocalDomain, user)
+	if err != nil {
+		return "", err
+	}
+
+	var invoice struct {
+		PaymentRequest string `json:"payreq"`
+	}
+	err = b.post(ctx, "/invoice", url.Values{
+		"msatoshi":    {fmt.Sprintf("%d", amountMsat)},
+		"description": {meta},
+	}, &invoice)
+	if err != nil {
+		return "", err
+	}
+
+	return invoice.PaymentRequest, nil
+}
+
+func (b *chargeBackend) Keysend(ctx context.Context, user string) (*KeysendMetadata, error) {
+	return nil, fmt.Errorf("charge backend: keysend is not supported")
+}
@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// clnBackend talks to Core Lightning's REST plugin (clnrest), authenticating
+// with a rune the operator generated for this proxy.
+type clnBackend struct {
+	cfg    Config
+	client *http.Client
+}
+
+func newCLNBackend(cfg Config) (Backend, error) {
+	if cfg.CLNRestURL == "" || cfg.CLNRune == "" {
+		return nil, fmt.Errorf("cln backend: %w: CLN_REST_URL and CLN_RUNE are required", ErrNotConfigured)
+	}
+	return &clnBackend{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (b *clnBackend) call(ctx context.Context, method string, body, out interface{}) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("cln backend: failed to encode %s request: %v", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.CLNRestURL+"/v1/"+method, bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("cln backend: failed to build %s request: %v", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Rune", b.cfg.CLNRune)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cln backend: %s request failed: %v", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("cln backend: %s returned %d", method, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// LNURLPMetadata returns the lnurlp well-known payload for user. It does
+// not set AllowsNostr/NostrPubkey: those would have to be signed with a
+// Nostr key this backend holds, and all it has is the node's Lightning
+// identity key, a different keypair that can never produce a valid NIP-57
+// zap receipt.
+func (b *clnBackend) LNURLPMetadata(ctx context.Context, user string) (*LNURLPMetadata, error) {
+	meta, err := lnurlpMetadataString(b.cfg.LocalDomain, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LNURLPMetadata{
+		Callback:    fmt.Sprintf("https://%s/.well-known/lnurlp/%s/callback", b.cfg.LocalDomain, user),
+		MinSendable: b.cfg.MinSendableMsat,
+		MaxSendable: b.cfg.MaxSendableMsat,
+		Metadata:    meta,
+		Tag:         "payRequest",
+	}, nil
+}
+
+func (b *clnBackend) LNURLPCallback(ctx context.Context, user string, amountMsat int64, comment string) (string, error) {
+	meta, err := lnurlpMetadataString(b.cfg.LocalDomain, user)
+	if err != nil {
+		return "", err
+	}
+
+	var invoice struct {
+		Bolt11 string `json:"bolt11"`
+	}
+	err = b.call(ctx, "invoice", map[string]interface{}{
+		"amount_msat":  amountMsat,
+		"label":        fmt.Sprintf("%s-%d-%d", user, amountMsat, time.Now().UnixNano()),
+		"description":  meta,
+		"deschashonly": true,
+	}, &invoice)
+	if err != nil {
+		return "", err
+	}
+
+	return invoice.Bolt11, nil
+}
+
+func (b *clnBackend) Keysend(ctx context.Context, user string) (*KeysendMetadata, error) {
+	var info struct {
+		ID string `json:"id"`
+	}
+	if err := b.call(ctx, "getinfo", map[string]interface{}{}, &info); err != nil {
+		return nil, err
+	}
+	return &KeysendMetadata{Pubkey: info.ID}, nil
+}
@@ -0,0 +1,97 @@
+package backend
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// lndBackend mints invoices by calling LND's gRPC API directly, so the
+// proxy can serve lnurlp/keysend for addresses backed by a node it
+// controls without going through a separate LNURL server.
+type lndBackend struct {
+	cfg    Config
+	client lnrpc.LightningClient
+	macHex string
+}
+
+func newLNDBackend(cfg Config) (Backend, error) {
+	if cfg.LNDAddress == "" || cfg.LNDMacaroonHex == "" {
+		return nil, fmt.Errorf("lnd backend: %w: LND_ADDRESS and LND_MACAROON_HEX are required", ErrNotConfigured)
+	}
+
+	creds := credentials.NewTLS(nil)
+	if cfg.LNDTLSCertPath != "" {
+		tlsCreds, err := credentials.NewClientTLSFromFile(cfg.LNDTLSCertPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("lnd backend: failed to load TLS cert: %v", err)
+		}
+		creds = tlsCreds
+	}
+
+	conn, err := grpc.Dial(cfg.LNDAddress, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("lnd backend: failed to dial %s: %v", cfg.LNDAddress, err)
+	}
+
+	return &lndBackend{cfg: cfg, client: lnrpc.NewLightningClient(conn), macHex: cfg.LNDMacaroonHex}, nil
+}
+
+func (b *lndBackend) authContext(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "macaroon", b.macHex)
+}
+
+// LNURLPMetadata returns the lnurlp well-known payload for user. It does
+// not set AllowsNostr/NostrPubkey: those would have to be signed with a
+// Nostr key this backend holds, and all it has is the node's Lightning
+// identity key, a different keypair that can never produce a valid NIP-57
+// zap receipt.
+func (b *lndBackend) LNURLPMetadata(ctx context.Context, user string) (*LNURLPMetadata, error) {
+	meta, err := lnurlpMetadataString(b.cfg.LocalDomain, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LNURLPMetadata{
+		Callback:    fmt.Sprintf("https://%s/.well-known/lnurlp/%s/callback", b.cfg.LocalDomain, user),
+		MinSendable: b.cfg.MinSendableMsat,
+		MaxSendable: b.cfg.MaxSendableMsat,
+		Metadata:    meta,
+		Tag:         "payRequest",
+	}, nil
+}
+
+func (b *lndBackend) LNURLPCallback(ctx context.Context, user string, amountMsat int64, comment string) (string, error) {
+	meta, err := lnurlpMetadataString(b.cfg.LocalDomain, user)
+	if err != nil {
+		return "", err
+	}
+
+	descHash, err := hex.DecodeString(descriptionHash(meta))
+	if err != nil {
+		return "", fmt.Errorf("lnd backend: invalid description hash: %v", err)
+	}
+
+	invoice, err := b.client.AddInvoice(b.authContext(ctx), &lnrpc.Invoice{
+		ValueMsat:       amountMsat,
+		DescriptionHash: descHash,
+	})
+	if err != nil {
+		return "", fmt.Errorf("lnd backend: AddInvoice: %v", err)
+	}
+
+	return invoice.PaymentRequest, nil
+}
+
+func (b *lndBackend) Keysend(ctx context.Context, user string) (*KeysendMetadata, error) {
+	info, err := b.client.GetInfo(b.authContext(ctx), &lnrpc.GetInfoRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("lnd backend: GetInfo: %v", err)
+	}
+	return &KeysendMetadata{Pubkey: info.IdentityPubkey}, nil
+}
@@ -0,0 +1,36 @@
+package backend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// lnurlpMetadataString builds the NIP-05-adjacent "metadata" field every
+// lnurlp response carries: a JSON-encoded array of [type, value] pairs,
+// always including a plain-text description so wallets have something to
+// show the user before they pay.
+//
+// It does not sign this metadata. Signing it (or a zap request, for
+// allowsNostr) needs a Nostr private key that none of these backends are
+// given - they only ever hold a Lightning node credential (macaroon/rune/
+// API token), a different kind of secret. Out of scope until that key
+// material has somewhere to live.
+func lnurlpMetadataString(domain, user string) (string, error) {
+	raw, err := json.Marshal([][2]string{
+		{"text/plain", fmt.Sprintf("Pay to %s@%s", user, domain)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode lnurlp metadata: %v", err)
+	}
+	return string(raw), nil
+}
+
+// descriptionHash is the sha256 of the metadata string, used as the
+// invoice description hash so wallets can prove the invoice matches the
+// metadata they were shown.
+func descriptionHash(metadata string) string {
+	sum := sha256.Sum256([]byte(metadata))
+	return hex.EncodeToString(sum[:])
+}
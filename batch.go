@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/matjaz/lightning-address-details-proxy/backend"
+)
+
+// BatchRequest is the body of POST /lightning-address-details/batch.
+type BatchRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+// BatchResponse wraps the per-address results of a batch resolution.
+type BatchResponse struct {
+	Results map[string]interface{} `json:"results"`
+}
+
+type batchItem struct {
+	Address string      `json:"address"`
+	Result  *LNResponse `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// resolveBatch fans the given addresses out over a bounded worker pool
+// (cfg.BatchConcurrency workers) so one huge batch cannot spawn thousands
+// of goroutines, reusing the same resolveLightningAddress used by the
+// single-address endpoint - including its cache and single-flight
+// coalescing. Results are delivered to onResult as each address finishes,
+// in no particular order, so callers can stream them or collect them all.
+func resolveBatch(ctx context.Context, addresses []string, cache Cache, cfg *Config, logger *log.Logger, be backend.Backend, onResult func(batchItem)) {
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+
+	workers := cfg.BatchConcurrency
+	if workers <= 0 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for address := range jobs {
+				result, _, _, err := resolveLightningAddress(ctx, address, cache, cfg, logger, be)
+				if err != nil {
+					onResult(batchItem{Address: address, Error: err.Error()})
+					continue
+				}
+				onResult(batchItem{Address: address, Result: result})
+			}
+		}()
+	}
+
+	for _, address := range addresses {
+		jobs <- address
+	}
+	close(jobs)
+
+	wg.Wait()
+}
+
+// BatchHandler resolves an entire list of lightning addresses at once,
+// capped at cfg.BatchMaxSize. When the client sends
+// "Accept: application/x-ndjson" results are streamed one JSON object per
+// line as they complete; otherwise the handler waits for the whole batch
+// and returns a single {"results": {...}} object, keyed by address.
+func BatchHandler(cfg *Config, cache Cache, logger *log.Logger, be backend.Backend) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req BatchRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		}
+
+		if len(req.Addresses) > cfg.BatchMaxSize {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "too many addresses in one batch",
+			})
+		}
+
+		// Each address's own upstream fetches already carry cfg.UpstreamTimeout
+		// individually; wrapping the whole batch in that same deadline would
+		// starve addresses queued behind a full worker pool, so only the
+		// client's own disconnect cancels the batch.
+		ctx := c.Request().Context()
+
+		if strings.Contains(c.Request().Header.Get(echo.HeaderAccept), "application/x-ndjson") {
+			c.Response().Header().Set(echo.HeaderContentType, "application/x-ndjson")
+			c.Response().WriteHeader(http.StatusOK)
+
+			encoder := json.NewEncoder(c.Response())
+			var mu sync.Mutex
+			resolveBatch(ctx, req.Addresses, cache, cfg, logger, be, func(item batchItem) {
+				mu.Lock()
+				defer mu.Unlock()
+				_ = encoder.Encode(item)
+				c.Response().Flush()
+			})
+			return nil
+		}
+
+		results := make(map[string]interface{}, len(req.Addresses))
+		var mu sync.Mutex
+		resolveBatch(ctx, req.Addresses, cache, cfg, logger, be, func(item batchItem) {
+			mu.Lock()
+			defer mu.Unlock()
+			if item.Error != "" {
+				results[item.Address] = map[string]string{"error": item.Error}
+			} else {
+				results[item.Address] = item.Result
+			}
+		})
+
+		return c.JSON(http.StatusOK, &BatchResponse{Results: results})
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"container/list"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStatus describes how a GetJSON lookup was served, surfaced to
+// clients via the X-Cache response header.
+type CacheStatus string
+
+const (
+	CacheMiss  CacheStatus = "MISS"
+	CacheHit   CacheStatus = "HIT"
+	CacheStale CacheStatus = "STALE"
+)
+
+// cacheEntry is a single cached upstream response, kept around past its
+// freshness window so it can be served stale if upstream starts failing.
+type cacheEntry struct {
+	body       interface{}
+	header     http.Header
+	statusCode int
+	storedAt   time.Time
+	expiresAt  time.Time
+	staleUntil time.Time
+}
+
+func (e *cacheEntry) fresh(now time.Time) bool {
+	return now.Before(e.expiresAt)
+}
+
+func (e *cacheEntry) servableStale(now time.Time) bool {
+	return now.Before(e.staleUntil)
+}
+
+func (e *cacheEntry) age(now time.Time) int {
+	if age := int(now.Sub(e.storedAt).Seconds()); age > 0 {
+		return age
+	}
+	return 0
+}
+
+// Cache stores upstream .well-known responses keyed by their (already
+// hostname-rewritten) URL.
+type Cache interface {
+	Get(key string) (*cacheEntry, bool)
+	Set(key string, entry *cacheEntry)
+}
+
+// noopCache disables caching entirely, e.g. for tests.
+type noopCache struct{}
+
+func (noopCache) Get(key string) (*cacheEntry, bool) { return nil, false }
+func (noopCache) Set(key string, entry *cacheEntry)  {}
+
+// lruCache is a bounded, in-memory cache that evicts the least recently
+// used entry once it grows past maxEntries.
+type lruCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+type lruItem struct {
+	key   string
+	entry *cacheEntry
+}
+
+func newLRUCache(maxEntries int) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *lruCache) Get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+func (c *lruCache) Set(key string, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&lruItem{key: key, entry: entry})
+	c.entries[key] = el
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+// NewCache builds the Cache implementation selected by Config. A
+// non-positive CacheMaxEntries disables caching.
+func NewCache(c *Config) Cache {
+	if c.CacheMaxEntries <= 0 {
+		return noopCache{}
+	}
+	return newLRUCache(c.CacheMaxEntries)
+}
+
+// resolveTTL derives how long a response may be served fresh, honoring
+// upstream Cache-Control/Expires headers and falling back to defaultTTL.
+func resolveTTL(header http.Header, defaultTTL time.Duration) time.Duration {
+	if cc := header.Get("Cache-Control"); cc != "" {
+		for _, directive := range strings.Split(cc, ",") {
+			directive = strings.TrimSpace(directive)
+			if !strings.HasPrefix(directive, "max-age=") {
+				continue
+			}
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		if t, err := http.ParseTime(expires); err == nil {
+			if ttl := time.Until(t); ttl > 0 {
+				return ttl
+			}
+			return 0
+		}
+	}
+
+	return defaultTTL
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestResolveTTL(t *testing.T) {
+	defaultTTL := 30 * time.Second
+
+	cases := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{
+			name:   "no headers falls back to default",
+			header: http.Header{},
+			want:   defaultTTL,
+		},
+		{
+			name:   "max-age is honored",
+			header: http.Header{"Cache-Control": {"public, max-age=60"}},
+			want:   60 * time.Second,
+		},
+		{
+			name:   "max-age among other directives",
+			header: http.Header{"Cache-Control": {"no-transform, max-age=15, must-revalidate"}},
+			want:   15 * time.Second,
+		},
+		{
+			name:   "non-numeric max-age falls back to default",
+			header: http.Header{"Cache-Control": {"max-age=soon"}},
+			want:   defaultTTL,
+		},
+		{
+			name:   "past Expires yields zero TTL",
+			header: http.Header{"Expires": {time.Now().Add(-time.Hour).Format(http.TimeFormat)}},
+			want:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := resolveTTL(tc.header, defaultTTL); got != tc.want {
+				t.Fatalf("resolveTTL() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveTTLFutureExpires(t *testing.T) {
+	header := http.Header{"Expires": {time.Now().Add(time.Hour).Format(http.TimeFormat)}}
+	got := resolveTTL(header, 30*time.Second)
+	if got <= 0 || got > time.Hour {
+		t.Fatalf("resolveTTL() = %v, want a positive duration close to 1h", got)
+	}
+}
+
+func TestLRUCacheGetSet(t *testing.T) {
+	cache := newLRUCache(2)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Get() on empty cache should miss")
+	}
+
+	cache.Set("a", &cacheEntry{statusCode: 200})
+	entry, ok := cache.Get("a")
+	if !ok || entry.statusCode != 200 {
+		t.Fatalf("Get(%q) = %v, %v, want the entry just Set", "a", entry, ok)
+	}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newLRUCache(2)
+
+	cache.Set("a", &cacheEntry{statusCode: 1})
+	cache.Set("b", &cacheEntry{statusCode: 2})
+	cache.Set("c", &cacheEntry{statusCode: 3})
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("expected \"a\" to have been evicted once the cache grew past maxEntries")
+	}
+	if _, ok := cache.Get("b"); !ok {
+		t.Fatal("expected \"b\" to still be cached")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Fatal("expected \"c\" to still be cached")
+	}
+}
+
+func TestLRUCacheGetRefreshesRecency(t *testing.T) {
+	cache := newLRUCache(2)
+
+	cache.Set("a", &cacheEntry{statusCode: 1})
+	cache.Set("b", &cacheEntry{statusCode: 2})
+
+	// Touching "a" should make "b" the least recently used entry.
+	cache.Get("a")
+	cache.Set("c", &cacheEntry{statusCode: 3})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("expected \"b\" to have been evicted after \"a\" was refreshed")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected \"a\" to still be cached")
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+// httpClient is shared by all upstream fetches so connections to popular
+// providers (getalby.com and friends) get reused instead of each request
+// paying a fresh TLS handshake, and so a single slow host can't pin
+// goroutines indefinitely via DefaultClient's lack of timeouts.
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 5 * time.Second,
+	},
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,14 +21,52 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/matjaz/lightning-address-details-proxy/backend"
+	"github.com/matjaz/lightning-address-details-proxy/nostr"
 )
 
 type Config struct {
-	SentryDSN   string `envconfig:"SENTRY_DSN"`
-	LogFilePath string `envconfig:"LOG_FILE_PATH"`
-	Port        int    `envconfig:"PORT" default:"3000"`
+	SentryDSN        string        `envconfig:"SENTRY_DSN"`
+	LogFilePath      string        `envconfig:"LOG_FILE_PATH"`
+	Port             int           `envconfig:"PORT" default:"3000"`
+	CacheTTL         time.Duration `envconfig:"CACHE_TTL" default:"5m"`
+	CacheMaxEntries  int           `envconfig:"CACHE_MAX_ENTRIES" default:"10000"`
+	CacheStaleWindow time.Duration `envconfig:"CACHE_STALE_WINDOW" default:"10m"`
+
+	RateLimitRPS            float64 `envconfig:"RATE_LIMIT_RPS" default:"5"`
+	RateLimitBurst          int     `envconfig:"RATE_LIMIT_BURST" default:"10"`
+	RateLimitTrustedProxies string  `envconfig:"RATE_LIMIT_TRUSTED_PROXIES"`
+	InvoiceRateLimitRPS     float64 `envconfig:"INVOICE_RATE_LIMIT_RPS" default:"1"`
+	InvoiceRateLimitBurst   int     `envconfig:"INVOICE_RATE_LIMIT_BURST" default:"2"`
+
+	UpstreamTimeout time.Duration `envconfig:"UPSTREAM_TIMEOUT" default:"5s"`
+
+	BatchMaxSize     int `envconfig:"BATCH_MAX_SIZE" default:"50"`
+	BatchConcurrency int `envconfig:"BATCH_CONCURRENCY" default:"16"`
+
+	Backend     string `envconfig:"BACKEND" default:"none"`
+	LocalDomain string `envconfig:"LOCAL_DOMAIN"`
+
+	MinSendableMsat int64 `envconfig:"MIN_SENDABLE_MSAT" default:"1000"`
+	MaxSendableMsat int64 `envconfig:"MAX_SENDABLE_MSAT" default:"1000000000"`
+
+	LNDAddress     string `envconfig:"LND_ADDRESS"`
+	LNDMacaroonHex string `envconfig:"LND_MACAROON_HEX"`
+	LNDTLSCertPath string `envconfig:"LND_TLS_CERT_PATH"`
+
+	CLNRestURL string `envconfig:"CLN_REST_URL"`
+	CLNRune    string `envconfig:"CLN_RUNE"`
+
+	LNChargeAPI   string `envconfig:"LN_CHARGE_API"`
+	LNChargeToken string `envconfig:"LN_CHARGE_TOKEN"`
 }
 
+// sfGroup coalesces concurrent GetJSON calls for the same upstream URL so a
+// thundering herd for one address hits upstream exactly once.
+var sfGroup singleflight.Group
+
 type LNResponse struct {
 	Lnurlp  interface{} `json:"lnurlp"`
 	Keysend interface{} `json:"keysend"`
@@ -36,14 +75,42 @@ type LNResponse struct {
 
 type GIResponse struct {
 	Invoice interface{} `json:"invoice"`
+	Zap     *ZapReceipt `json:"zap,omitempty"`
 }
 
 type GetJSONParams struct {
-	url string
-	wg  *sync.WaitGroup
+	ctx      context.Context
+	url      string
+	wg       *sync.WaitGroup
+	cache    Cache
+	cacheTTL time.Duration
+	staleFor time.Duration
+	timeout  time.Duration
 }
 
-func GetJSON(p GetJSONParams) (interface{}, *http.Response, error) {
+// fetchResult is what a single (possibly singleflight-shared) upstream
+// fetch produces, so it can be cached and replayed for cache hits.
+type fetchResult struct {
+	body       interface{}
+	header     http.Header
+	statusCode int
+}
+
+func responseFromEntry(entry *cacheEntry) *http.Response {
+	return &http.Response{
+		StatusCode: entry.statusCode,
+		Header:     entry.header,
+	}
+}
+
+// CacheOutcome reports how a GetJSON call was served, so handlers can
+// surface it via the X-Cache and Age response headers.
+type CacheOutcome struct {
+	Status CacheStatus
+	Age    int
+}
+
+func GetJSON(p GetJSONParams) (interface{}, *http.Response, CacheOutcome, error) {
 	if p.wg != nil {
 		defer p.wg.Done()
 	}
@@ -51,21 +118,75 @@ func GetJSON(p GetJSONParams) (interface{}, *http.Response, error) {
 	urlPrefix := "https://getalby.com"
 	replacement := "http://alby-mainnet-getalbycom"
 
-	url := strings.Replace(p.url, urlPrefix, replacement, 1)
+	key := strings.Replace(p.url, urlPrefix, replacement, 1)
+
+	cache := p.cache
+	if cache == nil {
+		cache = noopCache{}
+	}
+
+	now := time.Now()
+	if entry, ok := cache.Get(key); ok && entry.fresh(now) {
+		return entry.body, responseFromEntry(entry), CacheOutcome{CacheHit, entry.age(now)}, nil
+	}
+
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout := p.timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	var failedResponse *http.Response
+	v, err, _ := sfGroup.Do(key, func() (interface{}, error) {
+		reqCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
 
-	response, err := http.Get(url)
-	if err != nil || response.StatusCode > 300 {
-		return nil, response, fmt.Errorf("no details: %s - %v", p.url, err)
-	} else {
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, key, nil)
+		if err != nil {
+			return nil, fmt.Errorf("no details: %s - %v", p.url, err)
+		}
+
+		response, err := httpClient.Do(req)
+		if err != nil || response.StatusCode > 300 {
+			failedResponse = response
+			return nil, fmt.Errorf("no details: %s - %v", p.url, err)
+		}
 		defer response.Body.Close()
+
 		var j interface{}
-		err = json.NewDecoder(response.Body).Decode(&j)
-		if err != nil {
-			return nil, response, fmt.Errorf("invalid JSON: %v", err)
-		} else {
-			return j, response, nil
+		if err := json.NewDecoder(response.Body).Decode(&j); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
 		}
+
+		result := &fetchResult{body: j, header: response.Header, statusCode: response.StatusCode}
+
+		ttl := resolveTTL(response.Header, p.cacheTTL)
+		cache.Set(key, &cacheEntry{
+			body:       j,
+			header:     response.Header,
+			statusCode: response.StatusCode,
+			storedAt:   now,
+			expiresAt:  now.Add(ttl),
+			staleUntil: now.Add(ttl + p.staleFor),
+		})
+
+		return result, nil
+	})
+
+	if err != nil {
+		// Upstream failed: fall back to a stale cached copy if we still
+		// have one within the stale-while-revalidate window.
+		if entry, ok := cache.Get(key); ok && entry.servableStale(now) {
+			return entry.body, responseFromEntry(entry), CacheOutcome{CacheStale, entry.age(now)}, nil
+		}
+		return nil, failedResponse, CacheOutcome{Status: CacheMiss}, err
 	}
+
+	result := v.(*fetchResult)
+	return result.body, &http.Response{StatusCode: result.statusCode, Header: result.header}, CacheOutcome{Status: CacheMiss}, nil
 }
 
 func ToUrl(identifier string) (string, string, string, error) {
@@ -81,8 +202,186 @@ func ToUrl(identifier string) (string, string, string, error) {
 	return lnurlpUrl, keysendUrl, nostrUrl, nil
 }
 
+// resolveLightningAddress fetches lnurlp/keysend/nostr for a single
+// lightning address in parallel, the same way the /lightning-address-details
+// handler always has. It is shared with the batch endpoint so both paths
+// reuse the cache, the single-flight coalescing and the partial-success
+// semantics. The returned *http.Response is the lnurlp one, kept around so
+// callers can forward its Cache-Control header the way the handler does.
+func resolveLightningAddress(ctx context.Context, ln string, cache Cache, cfg *Config, logger *log.Logger, be backend.Backend) (*LNResponse, *http.Response, CacheOutcome, error) {
+	if host := addressHost(ln); cfg.LocalDomain != "" && host == cfg.LocalDomain {
+		responseBody, err := resolveLocalAddress(ctx, ln, be)
+		return responseBody, &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, CacheOutcome{}, err
+	}
+
+	responseBody := &LNResponse{}
+	var wg sync.WaitGroup
+	var lnurlp, keysend, nostr interface{}
+	var lnurlpResponse, keysendResponse, nostrResponse *http.Response
+	var lnurlpCache CacheOutcome
+
+	lnurlpUrl, keysendUrl, nostrUrl, err := ToUrl(ln)
+	if err != nil {
+		logger.WithFields(log.Fields{
+			"lightning_address": ln,
+		}).Errorf("Failed to parse urls: %v", err)
+		return responseBody, nil, lnurlpCache, err
+	}
+
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		var err error
+		lnurlp, lnurlpResponse, lnurlpCache, err = GetJSON(GetJSONParams{ctx: ctx, url: lnurlpUrl, cache: cache, cacheTTL: cfg.CacheTTL, staleFor: cfg.CacheStaleWindow, timeout: cfg.UpstreamTimeout})
+		if err != nil {
+			logger.WithFields(log.Fields{
+				"lightning_address": ln,
+				"lnurlp_url":        lnurlpUrl,
+			}).Errorf("Failed to fetch lnurlp response: %v", err)
+		} else {
+			responseBody.Lnurlp = lnurlp
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		var err error
+		keysend, keysendResponse, _, err = GetJSON(GetJSONParams{ctx: ctx, url: keysendUrl, cache: cache, cacheTTL: cfg.CacheTTL, staleFor: cfg.CacheStaleWindow, timeout: cfg.UpstreamTimeout})
+		if err != nil {
+			logger.WithFields(log.Fields{
+				"lightning_address": ln,
+				"keysend_url":       keysendUrl,
+			}).Errorf("Failed to fetch keysend response: %v", err)
+		} else {
+			responseBody.Keysend = keysend
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		var err error
+		nostr, nostrResponse, _, err = GetJSON(GetJSONParams{ctx: ctx, url: nostrUrl, cache: cache, cacheTTL: cfg.CacheTTL, staleFor: cfg.CacheStaleWindow, timeout: cfg.UpstreamTimeout})
+		if err != nil {
+			logger.WithFields(log.Fields{
+				"lightning_address": ln,
+				"nostr_url":         nostrUrl,
+			}).Errorf("Failed to fetch nostr response: %v", err)
+		} else {
+			responseBody.Nostr = nostr
+		}
+	}()
+
+	wg.Wait()
+
+	// if the requests resulted in errors return a bad request. something must be wrong with the ln address
+	lnurlpFailed := lnurlpResponse == nil || lnurlpResponse.StatusCode >= 300
+	keysendFailed := keysendResponse == nil || keysendResponse.StatusCode >= 300
+	nostrFailed := nostrResponse == nil || nostrResponse.StatusCode >= 300
+	if lnurlpFailed && keysendFailed && nostrFailed {
+		logger.WithFields(log.Fields{
+			"lightning_address": ln,
+		}).Errorf("Could not retrieve details for lightning address %v", ln)
+		return responseBody, lnurlpResponse, lnurlpCache, fmt.Errorf("could not retrieve details for lightning address %s", ln)
+	}
+
+	return responseBody, lnurlpResponse, lnurlpCache, nil
+}
+
+// addressHost returns the domain part of a "user@domain" lightning address,
+// or "" if it isn't well-formed.
+func addressHost(ln string) string {
+	parts := strings.Split(ln, "@")
+	if len(parts) != 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// resolveLocalAddress serves lnurlp/keysend for an address on
+// Config.LocalDomain directly from the configured backend instead of
+// proxying a remote .well-known fetch.
+func resolveLocalAddress(ctx context.Context, ln string, be backend.Backend) (*LNResponse, error) {
+	parts := strings.Split(ln, "@")
+	user := parts[0]
+
+	lnurlp, err := be.LNURLPMetadata(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("local backend lnurlp lookup failed for %s: %v", ln, err)
+	}
+
+	// A failed keysend lookup still returns lnurlp, as the remote path does.
+	keysend, err := be.Keysend(ctx, user)
+	if err != nil {
+		return &LNResponse{Lnurlp: lnurlp}, nil
+	}
+
+	return &LNResponse{Lnurlp: lnurlp, Keysend: keysend}, nil
+}
+
+// generateLocalInvoice mints an invoice for a locally-owned address
+// directly through the configured backend, mirroring the validation
+// /generate-invoice otherwise does against a remote lnurlp callback.
+func generateLocalInvoice(ctx context.Context, c echo.Context, ln string, be backend.Backend) error {
+	responseBody := &GIResponse{}
+
+	parts := strings.Split(ln, "@")
+	if len(parts) != 2 {
+		return c.JSON(http.StatusBadRequest, &responseBody)
+	}
+	user := parts[0]
+
+	metadata, err := be.LNURLPMetadata(ctx, user)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, &responseBody)
+	}
+
+	amountMsat, err := strconv.ParseInt(c.QueryParam("amount"), 10, 64)
+	if err != nil || amountMsat < metadata.MinSendable || amountMsat > metadata.MaxSendable {
+		return c.JSON(http.StatusBadRequest, &responseBody)
+	}
+
+	var zapEvent *nostr.Event
+	if nostrParam := c.QueryParam("nostr"); nostrParam != "" {
+		metadataMap, err := metadataToMap(metadata)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		zapEvent, err = validateZapRequest(metadataMap, c.QueryParam("amount"), nostrParam)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	bolt11, err := be.LNURLPCallback(ctx, user, amountMsat, c.QueryParam("comment"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, &responseBody)
+	}
+
+	responseBody.Invoice = bolt11
+	if zapEvent != nil {
+		responseBody.Zap = zapReceiptFrom(zapEvent)
+	}
+	return c.JSONPretty(http.StatusOK, &responseBody, "  ")
+}
+
+// metadataToMap round-trips a backend.LNURLPMetadata through JSON so it can
+// be fed to validateZapRequest, which expects the same generic
+// map[string]interface{} shape GetJSON returns for a remote lnurlp response.
+func metadataToMap(metadata *backend.LNURLPMetadata) (map[string]interface{}, error) {
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func main() {
-	c := &Config{}
+	cfg := &Config{}
 	logger := log.New()
 	logger.SetFormatter(&log.JSONFormatter{})
 
@@ -91,11 +390,33 @@ func main() {
 	if err != nil {
 		logger.Infof("Failed to load .env file: %v", err)
 	}
-	err = envconfig.Process("", c)
+	err = envconfig.Process("", cfg)
 	if err != nil {
 		logger.Fatalf("Error loading environment variables: %v", err)
 	}
 
+	cache := NewCache(cfg)
+	trustedProxies := parseTrustedProxies(cfg.RateLimitTrustedProxies)
+	rateLimit := RateLimitMiddleware(cfg.RateLimitRPS, cfg.RateLimitBurst, trustedProxies)
+	invoiceRateLimit := RateLimitMiddleware(cfg.InvoiceRateLimitRPS, cfg.InvoiceRateLimitBurst, trustedProxies)
+
+	be, err := backend.New(backend.Config{
+		Backend:         backend.Kind(cfg.Backend),
+		LocalDomain:     cfg.LocalDomain,
+		MinSendableMsat: cfg.MinSendableMsat,
+		MaxSendableMsat: cfg.MaxSendableMsat,
+		LNDAddress:      cfg.LNDAddress,
+		LNDMacaroonHex:  cfg.LNDMacaroonHex,
+		LNDTLSCertPath:  cfg.LNDTLSCertPath,
+		CLNRestURL:      cfg.CLNRestURL,
+		CLNRune:         cfg.CLNRune,
+		ChargeAPI:       cfg.LNChargeAPI,
+		ChargeToken:     cfg.LNChargeToken,
+	})
+	if err != nil {
+		logger.Fatalf("Error configuring backend: %v", err)
+	}
+
 	e := echo.New()
 	e.HideBanner = true
 	echologrus.Logger = logger
@@ -106,9 +427,9 @@ func main() {
 	e.Use(middleware.CORS())
 
 	// Setup exception tracking with Sentry if configured
-	if c.SentryDSN != "" {
+	if cfg.SentryDSN != "" {
 		if err = sentry.Init(sentry.ClientOptions{
-			Dsn:          c.SentryDSN,
+			Dsn:          cfg.SentryDSN,
 			IgnoreErrors: []string{"401"},
 		}); err != nil {
 			log.Printf("sentry init error: %v", err)
@@ -118,84 +439,47 @@ func main() {
 	}
 
 	e.GET("/lightning-address-details", func(c echo.Context) error {
-		responseBody := &LNResponse{}
-		var wg sync.WaitGroup
-		var lnurlp, keysend, nostr interface{}
-		var lnurlpResponse, keysendResponse, nostrResponse *http.Response
-
 		ln := c.QueryParam("ln")
-		lnurlpUrl, keysendUrl, nostrUrl, err := ToUrl(ln)
-		if err != nil {
-			logger.WithFields(log.Fields{
-				"lightning_address": ln,
-			}).Errorf("Failed to parse urls: %v", err)
-			return c.JSON(http.StatusBadRequest, &responseBody)
-		}
-
-		wg.Add(3)
 
-		go func() {
-			lnurlp, lnurlpResponse, err = GetJSON(GetJSONParams{url: lnurlpUrl, wg: &wg})
-			if err != nil {
-				logger.WithFields(log.Fields{
-					"lightning_address": ln,
-					"lnurlp_url":        lnurlpUrl,
-				}).Errorf("Failed to fetch lnurlp response: %v", err)
-			} else {
-				responseBody.Lnurlp = lnurlp
-			}
-		}()
-
-		go func() {
-			keysend, keysendResponse, err = GetJSON(GetJSONParams{url: keysendUrl, wg: &wg})
-			if err != nil {
-				logger.WithFields(log.Fields{
-					"lightning_address": ln,
-					"keysend_url":       keysendUrl,
-				}).Errorf("Failed to fetch keysend response: %v", err)
-			} else {
-				responseBody.Keysend = keysend
-			}
-		}()
+		// A parent deadline tied to the request context so a client
+		// disconnect or the overall timeout cancels all three lookups.
+		ctx, cancel := context.WithTimeout(c.Request().Context(), cfg.UpstreamTimeout)
+		defer cancel()
 
-		go func() {
-			nostr, nostrResponse, err = GetJSON(GetJSONParams{url: nostrUrl, wg: &wg})
-			if err != nil {
-				logger.WithFields(log.Fields{
-					"lightning_address": ln,
-					"nostr_url":         nostrUrl,
-				}).Errorf("Failed to fetch nostr response: %v", err)
-			} else {
-				responseBody.Nostr = nostr
-			}
-		}()
-
-		wg.Wait()
-
-		// if the requests resulted in errors return a bad request. something must be wrong with the ln address
-		if (lnurlpResponse == nil && keysendResponse == nil && nostrResponse == nil) ||
-			(lnurlpResponse.StatusCode >= 300 && keysendResponse.StatusCode >= 300 && nostrResponse.StatusCode >= 300) {
-			logger.WithFields(log.Fields{
-				"lightning_address": ln,
-			}).Errorf("Could not retrieve details for lightning address %v", ln)
+		responseBody, lnurlpResponse, lnurlpCache, err := resolveLightningAddress(ctx, ln, cache, cfg, logger, be)
+		if err != nil {
 			return c.JSON(http.StatusBadRequest, &responseBody)
 		}
 
-		c.Response().Header().Set(echo.HeaderCacheControl, lnurlpResponse.Header.Get("Cache-Control"))
+		if lnurlpResponse != nil {
+			c.Response().Header().Set(echo.HeaderCacheControl, lnurlpResponse.Header.Get("Cache-Control"))
+		}
+		if lnurlpCache.Status != "" {
+			c.Response().Header().Set("X-Cache", string(lnurlpCache.Status))
+			c.Response().Header().Set("Age", strconv.Itoa(lnurlpCache.Age))
+		}
 		// default return response
 		return c.JSONPretty(http.StatusOK, &responseBody, "  ")
-	})
+	}, rateLimit)
 
 	e.GET("/generate-invoice", func(c echo.Context) error {
 		responseBody := &GIResponse{}
 
 		ln := c.QueryParam("ln")
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), cfg.UpstreamTimeout)
+		defer cancel()
+
+		if host := addressHost(ln); cfg.LocalDomain != "" && host == cfg.LocalDomain {
+			return generateLocalInvoice(ctx, c, ln, be)
+		}
+
 		lnurlpUrl, _, _, err := ToUrl(ln)
 		if err != nil {
 			return c.JSON(http.StatusBadRequest, &responseBody)
 		}
 
-		lnurlp, lnurlpResponse, err := GetJSON(GetJSONParams{url: lnurlpUrl})
+		lnurlp, lnurlpResponse, _, err := GetJSON(GetJSONParams{ctx: ctx, url: lnurlpUrl, cache: cache, cacheTTL: cfg.CacheTTL, staleFor: cfg.CacheStaleWindow, timeout: cfg.UpstreamTimeout})
 		if err != nil {
 			logger.WithFields(log.Fields{
 				"lightning_address": ln,
@@ -218,6 +502,17 @@ func main() {
 			return c.JSON(http.StatusBadRequest, &responseBody)
 		}
 
+		var zapEvent *nostr.Event
+		if nostrParam := c.QueryParam("nostr"); nostrParam != "" {
+			zapEvent, err = validateZapRequest(lnurlp, c.QueryParam("amount"), nostrParam)
+			if err != nil {
+				logger.WithFields(log.Fields{
+					"lightning_address": ln,
+				}).Errorf("Invalid zap request: %v", err)
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+		}
+
 		c.QueryParams().Del("ln")
 		invoiceParams := c.QueryParams()
 		invoiceUrl, err := url.Parse(callback.(string))
@@ -234,13 +529,16 @@ func main() {
 			}
 		}
 		invoiceUrl.RawQuery = values.Encode()
-		invoice, invoiceResponse, err := GetJSON(GetJSONParams{url: invoiceUrl.String()})
+		invoice, invoiceResponse, _, err := GetJSON(GetJSONParams{ctx: ctx, url: invoiceUrl.String(), timeout: cfg.UpstreamTimeout})
 		if err != nil {
 			logger.WithFields(log.Fields{
 				"lightning_address": ln,
 			}).Errorf("Failed to fetch invoice: %v", err)
 		} else {
 			responseBody.Invoice = invoice
+			if zapEvent != nil {
+				responseBody.Zap = zapReceiptFrom(zapEvent)
+			}
 		}
 
 		if invoiceResponse == nil {
@@ -252,11 +550,32 @@ func main() {
 
 		// default return response
 		return c.JSONPretty(http.StatusOK, &responseBody, "  ")
-	})
+	}, invoiceRateLimit)
+
+	e.POST("/lightning-address-details/batch", BatchHandler(cfg, cache, logger, be), rateLimit)
+
+	e.GET("/.well-known/lnurlp/:user", func(c echo.Context) error {
+		metadata, err := be.LNURLPMetadata(c.Request().Context(), c.Param("user"))
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "unknown address"})
+		}
+		return c.JSON(http.StatusOK, metadata)
+	}, rateLimit)
+	e.GET("/.well-known/lnurlp/:user/callback", func(c echo.Context) error {
+		ln := c.Param("user") + "@" + cfg.LocalDomain
+		return generateLocalInvoice(c.Request().Context(), c, ln, be)
+	}, invoiceRateLimit)
+	e.GET("/.well-known/keysend/:user", func(c echo.Context) error {
+		metadata, err := be.Keysend(c.Request().Context(), c.Param("user"))
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "unknown address"})
+		}
+		return c.JSON(http.StatusOK, metadata)
+	}, rateLimit)
 
 	// Start server
 	go func() {
-		if err := e.Start(fmt.Sprintf(":%v", c.Port)); err != nil && err != http.ErrServerClosed {
+		if err := e.Start(fmt.Sprintf(":%v", cfg.Port)); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("shutting down the server", err)
 		}
 	}()
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/matjaz/lightning-address-details-proxy/backend"
+)
+
+// partialFailureTransport simulates a lnurlp endpoint that is unreachable
+// (the way a network error/timeout surfaces through http.Client.Do: a nil
+// *http.Response) while keysend and nostr.json both succeed.
+type partialFailureTransport struct{}
+
+func (partialFailureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch {
+	case strings.Contains(req.URL.Path, "/.well-known/lnurlp/"):
+		return nil, fmt.Errorf("simulated network failure")
+	case strings.Contains(req.URL.Path, "/.well-known/keysend/"):
+		return jsonStubResponse(`{"pubkey":"abc"}`), nil
+	case strings.Contains(req.URL.Path, "/.well-known/nostr.json"):
+		return jsonStubResponse(`{"names":{}}`), nil
+	default:
+		return nil, fmt.Errorf("unexpected request to %s", req.URL)
+	}
+}
+
+func jsonStubResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestResolveLightningAddressPartialLnurlpFailure(t *testing.T) {
+	originalTransport := httpClient.Transport
+	httpClient.Transport = partialFailureTransport{}
+	defer func() { httpClient.Transport = originalTransport }()
+
+	cfg := &Config{UpstreamTimeout: time.Second, CacheTTL: time.Minute, CacheStaleWindow: time.Minute}
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	be, err := backend.New(backend.Config{Backend: backend.KindNone})
+	if err != nil {
+		t.Fatalf("backend.New() error: %v", err)
+	}
+
+	responseBody, lnurlpResponse, _, err := resolveLightningAddress(context.Background(), "user@example.com", noopCache{}, cfg, logger, be)
+	if err != nil {
+		t.Fatalf("expected a partial success, got error: %v", err)
+	}
+	if lnurlpResponse != nil {
+		t.Fatalf("expected a nil lnurlp response, got %+v", lnurlpResponse)
+	}
+	if responseBody.Keysend == nil {
+		t.Fatal("expected keysend to have resolved despite the lnurlp failure")
+	}
+	if responseBody.Nostr == nil {
+		t.Fatal("expected nostr.json to have resolved despite the lnurlp failure")
+	}
+	if responseBody.Lnurlp != nil {
+		t.Fatal("expected lnurlp to be unset since it failed")
+	}
+}
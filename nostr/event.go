@@ -0,0 +1,103 @@
+// Package nostr implements just enough of NIP-01/NIP-57 to verify a zap
+// request event attached to a /generate-invoice call: event hashing,
+// schnorr signature verification, and the couple of tag lookups the zap
+// flow needs.
+package nostr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+// KindZapRequest is the NIP-57 zap request event kind.
+const KindZapRequest = 9734
+
+// Event is a NIP-01 event, trimmed to the fields NIP-57 verification needs.
+type Event struct {
+	ID        string     `json:"id"`
+	PubKey    string     `json:"pubkey"`
+	CreatedAt int64      `json:"created_at"`
+	Kind      int        `json:"kind"`
+	Tags      [][]string `json:"tags"`
+	Content   string     `json:"content"`
+	Sig       string     `json:"sig"`
+}
+
+// serialize produces the NIP-01 canonical form used to derive the event ID:
+// [0, pubkey, created_at, kind, tags, content].
+func (e *Event) serialize() ([]byte, error) {
+	return json.Marshal([]interface{}{0, e.PubKey, e.CreatedAt, e.Kind, e.Tags, e.Content})
+}
+
+// hash returns the sha256 of the event's canonical serialization, which
+// NIP-01 defines as the event ID.
+func (e *Event) hash() ([32]byte, error) {
+	raw, err := e.serialize()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(raw), nil
+}
+
+// Verify checks that the event ID matches its content hash and that Sig is
+// a valid schnorr signature over that hash by PubKey.
+func (e *Event) Verify() error {
+	hash, err := e.hash()
+	if err != nil {
+		return fmt.Errorf("failed to serialize event: %v", err)
+	}
+
+	if hex.EncodeToString(hash[:]) != e.ID {
+		return fmt.Errorf("event id does not match its content")
+	}
+
+	pubKeyBytes, err := hex.DecodeString(e.PubKey)
+	if err != nil {
+		return fmt.Errorf("invalid pubkey: %v", err)
+	}
+	pubKey, err := schnorr.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return fmt.Errorf("invalid pubkey: %v", err)
+	}
+
+	sigBytes, err := hex.DecodeString(e.Sig)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	sig, err := schnorr.ParseSignature(sigBytes)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+
+	if !sig.Verify(hash[:], pubKey) {
+		return fmt.Errorf("signature verification failed")
+	}
+
+	return nil
+}
+
+// Tag returns the value of the first tag named key (e.g. "p", "e",
+// "amount"), or "" if absent.
+func (e *Event) Tag(key string) string {
+	for _, tag := range e.Tags {
+		if len(tag) >= 2 && tag[0] == key {
+			return tag[1]
+		}
+	}
+	return ""
+}
+
+// Relays returns the values of the "relays" tag, which NIP-57 requires the
+// zap request to carry so the receipt can be published afterwards.
+func (e *Event) Relays() []string {
+	for _, tag := range e.Tags {
+		if len(tag) >= 1 && tag[0] == "relays" {
+			return tag[1:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/didip/tollbooth/v6"
+	"github.com/didip/tollbooth/v6/limiter"
+	"github.com/labstack/echo/v4"
+)
+
+// bucketExpirationTTL bounds how long an idle (IP, lightning-address)
+// token bucket is kept around. Left at tollbooth's own default (10 years,
+// unbounded size) every distinct pair ever seen would sit in memory
+// forever, so buckets are evicted shortly after the traffic they were
+// shaping stops.
+const bucketExpirationTTL = 2 * time.Minute
+
+// rateLimitKey identifies the caller a bucket applies to: their IP plus,
+// when present, the lightning address they're asking about. This stops a
+// single misbehaving client from monopolizing one popular address from
+// behind a shared IP, and vice versa.
+func rateLimitKey(c echo.Context, trustedProxies []*net.IPNet) string {
+	return clientIP(c, trustedProxies) + "|" + c.QueryParam("ln")
+}
+
+// clientIP returns the RemoteAddr, or the left-most X-Forwarded-For entry
+// when the direct peer is a trusted proxy.
+func clientIP(c echo.Context, trustedProxies []*net.IPNet) string {
+	remoteAddr := c.Request().RemoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		remoteAddr = host
+	}
+	remoteIP := net.ParseIP(remoteAddr)
+	if remoteIP == nil || !ipIsTrusted(remoteIP, trustedProxies) {
+		return remoteAddr
+	}
+
+	if fwd := c.Request().Header.Get(echo.HeaderXForwardedFor); fwd != "" {
+		return strings.TrimSpace(strings.Split(fwd, ",")[0])
+	}
+
+	return remoteAddr
+}
+
+func ipIsTrusted(ip net.IP, trustedProxies []*net.IPNet) bool {
+	for _, cidr := range trustedProxies {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies turns a comma-separated CIDR list (e.g.
+// RATE_LIMIT_TRUSTED_PROXIES) into parsed networks, skipping invalid
+// entries rather than failing startup.
+func parseTrustedProxies(cidrs string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, raw := range strings.Split(cidrs, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(raw); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// RateLimitMiddleware builds a tollbooth-backed Echo middleware that limits
+// requests per rateLimitKey to rps (with the given burst), responding 429
+// with a Retry-After header and a structured JSON error once exceeded.
+func RateLimitMiddleware(rps float64, burst int, trustedProxies []*net.IPNet) echo.MiddlewareFunc {
+	lmt := tollbooth.NewLimiter(rps, &limiter.ExpirableOptions{DefaultExpirationTTL: bucketExpirationTTL})
+	lmt.SetBurst(burst)
+	lmt.SetMessageContentType("application/json")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := rateLimitKey(c, trustedProxies)
+			if httpErr := tollbooth.LimitByKeys(lmt, []string{key}); httpErr != nil {
+				c.Response().Header().Set("Retry-After", "1")
+				return c.JSON(httpErr.StatusCode, map[string]string{
+					"error": "rate limit exceeded, please slow down",
+				})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/matjaz/lightning-address-details-proxy/nostr"
+)
+
+// ZapReceipt carries the parts of a validated NIP-57 zap request that the
+// caller needs to publish the zap receipt once the invoice is paid.
+type ZapReceipt struct {
+	P      string   `json:"p"`
+	E      string   `json:"e,omitempty"`
+	Relays []string `json:"relays"`
+}
+
+// validateZapRequest parses and validates a NIP-57 zap request event
+// carried by the "nostr" query param of /generate-invoice. lnurlpMetadata
+// is the decoded lnurlp response for the recipient; amountParam is the raw
+// "amount" query param (millisats). It returns the parsed event so the
+// caller can build a ZapReceipt from it.
+func validateZapRequest(lnurlpMetadata interface{}, amountParam, nostrParam string) (*nostr.Event, error) {
+	metadata, ok := lnurlpMetadata.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("lnurlp response is not an object")
+	}
+
+	if allows, _ := metadata["allowsNostr"].(bool); !allows {
+		return nil, fmt.Errorf("recipient does not advertise allowsNostr")
+	}
+	nostrPubkey, _ := metadata["nostrPubkey"].(string)
+	if nostrPubkey == "" {
+		return nil, fmt.Errorf("recipient does not advertise a nostrPubkey")
+	}
+
+	var event nostr.Event
+	if err := json.Unmarshal([]byte(nostrParam), &event); err != nil {
+		return nil, fmt.Errorf("invalid zap request event: %v", err)
+	}
+
+	if event.Kind != nostr.KindZapRequest {
+		return nil, fmt.Errorf("zap request must be kind %d, got %d", nostr.KindZapRequest, event.Kind)
+	}
+
+	if err := event.Verify(); err != nil {
+		return nil, fmt.Errorf("zap request signature invalid: %v", err)
+	}
+
+	if len(event.Relays()) == 0 {
+		return nil, fmt.Errorf("zap request is missing a relays tag")
+	}
+
+	if amount := event.Tag("amount"); amount != amountParam {
+		return nil, fmt.Errorf("zap request amount tag %q does not match amount=%q", amount, amountParam)
+	}
+
+	if p := event.Tag("p"); p != nostrPubkey {
+		return nil, fmt.Errorf("zap request p tag %q does not match recipient nostrPubkey %q", p, nostrPubkey)
+	}
+
+	return &event, nil
+}
+
+// zapReceiptFrom builds the ZapReceipt surfaced in GIResponse from a
+// validated zap request event.
+func zapReceiptFrom(event *nostr.Event) *ZapReceipt {
+	return &ZapReceipt{
+		P:      event.Tag("p"),
+		E:      event.Tag("e"),
+		Relays: event.Relays(),
+	}
+}